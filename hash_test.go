@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/sha256"
+	"testing"
+)
+
+func TestNewHasherUnknownAlgorithmErrors(t *testing.T) {
+	if _, err := newHasher("sha3000"); err == nil {
+		t.Fatal("expected an error for an unknown algorithm")
+	}
+}
+
+func TestNewHasherKnownAlgorithms(t *testing.T) {
+	for _, algo := range []string{"md5", "sha1", "sha256", "sha512", "blake2b", "blake2b-256"} {
+		if _, err := newHasher(algo); err != nil {
+			t.Fatalf("newHasher(%q) error = %v", algo, err)
+		}
+	}
+}
+
+func TestNewHasherBlake2bDefaultsToFull512BitDigest(t *testing.T) {
+	full, err := newHasher("blake2b")
+	if err != nil {
+		t.Fatalf("newHasher(blake2b) error = %v", err)
+	}
+	if got := full.Size(); got != 64 {
+		t.Fatalf("blake2b digest size = %d bytes, want 64 (512 bits)", got)
+	}
+
+	truncated, err := newHasher("blake2b-256")
+	if err != nil {
+		t.Fatalf("newHasher(blake2b-256) error = %v", err)
+	}
+	if got := truncated.Size(); got != 32 {
+		t.Fatalf("blake2b-256 digest size = %d bytes, want 32 (256 bits)", got)
+	}
+}
+
+func TestEncodeDigest(t *testing.T) {
+	sum := sha256.Sum256([]byte("hello"))
+
+	hexDigest, err := encodeDigest(sum[:], "hex")
+	if err != nil {
+		t.Fatalf("encodeDigest(hex) error = %v", err)
+	}
+	if hexDigest != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Fatalf("unexpected hex digest: %s", hexDigest)
+	}
+
+	b64Digest, err := encodeDigest(sum[:], "base64")
+	if err != nil {
+		t.Fatalf("encodeDigest(base64) error = %v", err)
+	}
+	if b64Digest != "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=" {
+		t.Fatalf("unexpected base64 digest: %s", b64Digest)
+	}
+
+	if _, err := encodeDigest(sum[:], "bogus"); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
+
+func TestDigestMatches(t *testing.T) {
+	tests := []struct {
+		format    string
+		got, want string
+		matches   bool
+	}{
+		{"hex", "2cf24dba", "2CF24DBA", true},
+		{"base64", "LPJNul+w", "lpjnul+w", false},
+		{"base64", "LPJNul+w", "LPJNul+w", true},
+		{"raw", "abc", "ABC", false},
+		{"raw", "abc", "abc", true},
+	}
+
+	for _, tt := range tests {
+		if got := digestMatches(tt.format, tt.got, tt.want); got != tt.matches {
+			t.Errorf("digestMatches(%q, %q, %q) = %v, want %v", tt.format, tt.got, tt.want, got, tt.matches)
+		}
+	}
+}
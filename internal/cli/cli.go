@@ -0,0 +1,158 @@
+// Package cli provides the small subcommand framework dev-tools is built on:
+// nested commands, command aliases, mutually-exclusive flag groups, and
+// shell-completion generation.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pechorka/gostdlib/pkg/errs"
+)
+
+// Command is a single CLI command. A Command with Subcommands dispatches to
+// one of them by name/alias; a Command with Run is a leaf that does work.
+type Command struct {
+	Name     string
+	Aliases  []string
+	Summary  string
+	Examples []string
+
+	FS          *flag.FlagSet
+	Subcommands []*Command
+	Run         func(ctx context.Context, args []string) error
+
+	exclusiveGroups [][]string
+}
+
+// New creates a Command with its own flag.FlagSet named after it.
+func New(name, summary string) *Command {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // we print our own usage on error
+
+	return &Command{
+		Name:    name,
+		Summary: summary,
+		FS:      fs,
+	}
+}
+
+// AddSubcommand registers sub as one of c's subcommands.
+func (c *Command) AddSubcommand(sub *Command) {
+	c.Subcommands = append(c.Subcommands, sub)
+}
+
+// MutuallyExclusive records a set of flag names of which at most one may be
+// passed explicitly on the command line. Execute validates this after
+// parsing.
+func (c *Command) MutuallyExclusive(flagNames ...string) {
+	c.exclusiveGroups = append(c.exclusiveGroups, flagNames)
+}
+
+// Execute parses args against c, dispatching to a subcommand when the first
+// positional argument names one, otherwise parsing flags and invoking Run.
+func (c *Command) Execute(ctx context.Context, args []string) error {
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+		c.PrintHelp(os.Stderr)
+		return nil
+	}
+
+	if len(c.Subcommands) > 0 && len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, rest := args[0], args[1:]
+		sub := c.find(name)
+		if sub == nil {
+			return errs.Errorf("%s: unknown command %q", c.Name, name)
+		}
+		return sub.Execute(ctx, rest)
+	}
+
+	if err := c.FS.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			c.PrintHelp(os.Stderr)
+			return nil
+		}
+		return errs.Wrapf(err, "%s: failed to parse flags", c.Name)
+	}
+
+	if err := c.checkExclusiveGroups(); err != nil {
+		return err
+	}
+
+	if c.Run == nil {
+		c.PrintHelp(os.Stderr)
+		return errs.Errorf("%s: missing subcommand", c.Name)
+	}
+
+	return c.Run(ctx, c.FS.Args())
+}
+
+func (c *Command) find(name string) *Command {
+	for _, sub := range c.Subcommands {
+		if sub.Name == name {
+			return sub
+		}
+		for _, alias := range sub.Aliases {
+			if alias == name {
+				return sub
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Command) checkExclusiveGroups() error {
+	explicit := map[string]bool{}
+	c.FS.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	for _, group := range c.exclusiveGroups {
+		var set []string
+		for _, name := range group {
+			if explicit[name] {
+				set = append(set, name)
+			}
+		}
+		if len(set) > 1 {
+			sort.Strings(set)
+			return errs.Errorf("%s: flags -%s are mutually exclusive", c.Name, strings.Join(set, ", -"))
+		}
+	}
+
+	return nil
+}
+
+// PrintHelp writes usage, subcommands, flags, and examples for c to w.
+func (c *Command) PrintHelp(w io.Writer) {
+	fmt.Fprintf(w, "%s - %s\n\n", c.Name, c.Summary)
+
+	fmt.Fprintf(w, "Usage:\n  %s", c.Name)
+	if len(c.Subcommands) > 0 {
+		fmt.Fprint(w, " <command>")
+	}
+	fmt.Fprintln(w, " [flags]")
+
+	if len(c.Subcommands) > 0 {
+		fmt.Fprintln(w, "\nCommands:")
+		for _, sub := range c.Subcommands {
+			names := append([]string{sub.Name}, sub.Aliases...)
+			fmt.Fprintf(w, "  %-20s %s\n", strings.Join(names, ", "), sub.Summary)
+		}
+	}
+
+	fmt.Fprintln(w, "\nFlags:")
+	c.FS.SetOutput(w)
+	c.FS.PrintDefaults()
+	c.FS.SetOutput(io.Discard)
+
+	if len(c.Examples) > 0 {
+		fmt.Fprintln(w, "\nExamples:")
+		for _, ex := range c.Examples {
+			fmt.Fprintf(w, "  %s\n", ex)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"context"
+	"testing"
+)
+
+func TestExecuteDispatchesToSubcommandByNameOrAlias(t *testing.T) {
+	var got string
+
+	root := New("dev-tools", "root")
+	leaf := New("base64", "encode/decode base64")
+	leaf.Aliases = []string{"b64"}
+	leaf.Run = func(ctx context.Context, args []string) error {
+		got = "ran"
+		return nil
+	}
+	root.AddSubcommand(leaf)
+
+	if err := root.Execute(context.Background(), []string{"b64"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got != "ran" {
+		t.Fatalf("expected subcommand to run, got %q", got)
+	}
+
+	got = ""
+	if err := root.Execute(context.Background(), []string{"base64"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got != "ran" {
+		t.Fatalf("expected subcommand to run by name, got %q", got)
+	}
+}
+
+func TestExecuteUnknownSubcommandErrors(t *testing.T) {
+	root := New("dev-tools", "root")
+	root.AddSubcommand(New("base64", "encode/decode base64"))
+
+	err := root.Execute(context.Background(), []string{"nope"})
+	if err == nil {
+		t.Fatal("expected an error for an unknown command")
+	}
+}
+
+func TestExecutePassesFlagsAndPositionalArgsToRun(t *testing.T) {
+	var gotFlag string
+	var gotArgs []string
+
+	cmd := New("hash", "hash input")
+	f := cmd.FS.String("algo", "sha256", "algorithm")
+	cmd.Run = func(ctx context.Context, args []string) error {
+		gotFlag = *f
+		gotArgs = args
+		return nil
+	}
+
+	err := cmd.Execute(context.Background(), []string{"-algo", "md5", "extra"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if gotFlag != "md5" {
+		t.Fatalf("expected -algo=md5, got %q", gotFlag)
+	}
+	if len(gotArgs) != 1 || gotArgs[0] != "extra" {
+		t.Fatalf("expected positional args [extra], got %v", gotArgs)
+	}
+}
+
+func TestMutuallyExclusiveRejectsBothFlagsSet(t *testing.T) {
+	cmd := New("uuid", "generate a uuid")
+	cmd.FS.Bool("v4", true, "generate v4 uuid")
+	cmd.FS.Bool("v7", false, "generate v7 uuid")
+	cmd.MutuallyExclusive("v4", "v7")
+	cmd.Run = func(ctx context.Context, args []string) error { return nil }
+
+	err := cmd.Execute(context.Background(), []string{"-v4", "-v7"})
+	if err == nil {
+		t.Fatal("expected an error when both mutually exclusive flags are set")
+	}
+}
+
+func TestMutuallyExclusiveAllowsOneFlagSet(t *testing.T) {
+	cmd := New("uuid", "generate a uuid")
+	cmd.FS.Bool("v4", true, "generate v4 uuid")
+	cmd.FS.Bool("v7", false, "generate v7 uuid")
+	cmd.MutuallyExclusive("v4", "v7")
+
+	var ran bool
+	cmd.Run = func(ctx context.Context, args []string) error {
+		ran = true
+		return nil
+	}
+
+	if err := cmd.Execute(context.Background(), []string{"-v7"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !ran {
+		t.Fatal("expected Run to be called")
+	}
+}
+
+func TestExecuteRoutesHelpFlagAfterOtherFlagsToPrintHelp(t *testing.T) {
+	var ran bool
+
+	cmd := New("hash", "hash input")
+	cmd.FS.String("algo", "sha256", "algorithm")
+	cmd.Run = func(ctx context.Context, args []string) error {
+		ran = true
+		return nil
+	}
+
+	err := cmd.Execute(context.Background(), []string{"-algo", "sha256", "-h"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if ran {
+		t.Fatal("expected Run not to be called when -h is passed")
+	}
+}
+
+func TestCompletionGeneratesScriptPerShell(t *testing.T) {
+	root := New("dev-tools", "root")
+	root.AddSubcommand(New("base64", "encode/decode base64"))
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		out, err := Completion(root, shell)
+		if err != nil {
+			t.Fatalf("Completion(%q) error = %v", shell, err)
+		}
+		if out == "" {
+			t.Fatalf("Completion(%q) returned empty script", shell)
+		}
+	}
+}
+
+func TestCompletionUnsupportedShellErrors(t *testing.T) {
+	root := New("dev-tools", "root")
+	if _, err := Completion(root, "powershell"); err == nil {
+		t.Fatal("expected an error for an unsupported shell")
+	}
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/pechorka/gostdlib/pkg/errs"
+)
+
+// Completion renders a shell completion script for root. shell must be one
+// of "bash", "zsh", "fish".
+func Completion(root *Command, shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return bashCompletion(root), nil
+	case "zsh":
+		return zshCompletion(root), nil
+	case "fish":
+		return fishCompletion(root), nil
+	default:
+		return "", errs.Errorf("%s is unsupported shell", shell)
+	}
+}
+
+func commandNames(root *Command) []string {
+	names := make([]string, 0, len(root.Subcommands))
+	for _, sub := range root.Subcommands {
+		names = append(names, sub.Name)
+		names = append(names, sub.Aliases...)
+	}
+	return names
+}
+
+func flagNames(root *Command) []string {
+	var names []string
+	for _, sub := range root.Subcommands {
+		sub.FS.VisitAll(func(f *flag.Flag) {
+			names = append(names, "-"+f.Name)
+		})
+	}
+	return names
+}
+
+func bashCompletion(root *Command) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s bash completion\n", root.Name)
+	fmt.Fprintf(&sb, "_%s_completions() {\n", root.Name)
+	fmt.Fprintln(&sb, `  local cur prev words`)
+	fmt.Fprintln(&sb, `  cur="${COMP_WORDS[COMP_CWORD]}"`)
+	fmt.Fprintf(&sb, "  words=\"%s\"\n", strings.Join(commandNames(root), " "))
+	fmt.Fprintln(&sb, `  if [ "$COMP_CWORD" -eq 1 ]; then`)
+	fmt.Fprintln(&sb, `    COMPREPLY=( $(compgen -W "$words" -- "$cur") )`)
+	fmt.Fprintln(&sb, `    return`)
+	fmt.Fprintln(&sb, `  fi`)
+	fmt.Fprintf(&sb, "  COMPREPLY=( $(compgen -W \"%s\" -- \"$cur\") )\n", strings.Join(flagNames(root), " "))
+	fmt.Fprintln(&sb, "}")
+	fmt.Fprintf(&sb, "complete -F _%s_completions %s\n", root.Name, root.Name)
+	return sb.String()
+}
+
+func zshCompletion(root *Command) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "#compdef %s\n", root.Name)
+	fmt.Fprintf(&sb, "_%s() {\n", root.Name)
+	fmt.Fprintln(&sb, `  local -a commands`)
+	fmt.Fprintln(&sb, "  commands=(")
+	for _, sub := range root.Subcommands {
+		fmt.Fprintf(&sb, "    %q\n", sub.Name+":"+sub.Summary)
+	}
+	fmt.Fprintln(&sb, "  )")
+	fmt.Fprintln(&sb, `  _describe "command" commands`)
+	fmt.Fprintln(&sb, "}")
+	fmt.Fprintf(&sb, "compdef _%s %s\n", root.Name, root.Name)
+	return sb.String()
+}
+
+func fishCompletion(root *Command) string {
+	var sb strings.Builder
+	for _, sub := range root.Subcommands {
+		fmt.Fprintf(&sb, "complete -c %s -n \"__fish_use_subcommand\" -a %s -d %q\n", root.Name, sub.Name, sub.Summary)
+		for _, alias := range sub.Aliases {
+			fmt.Fprintf(&sb, "complete -c %s -n \"__fish_use_subcommand\" -a %s -d %q\n", root.Name, alias, sub.Summary)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJwtSegments(t *testing.T) {
+	header, payload, sig, err := jwtSegments("aaa.bbb.ccc")
+	if err != nil {
+		t.Fatalf("jwtSegments() error = %v", err)
+	}
+	if header != "aaa" || payload != "bbb" || sig != "ccc" {
+		t.Fatalf("jwtSegments() = (%q, %q, %q)", header, payload, sig)
+	}
+
+	if _, _, _, err := jwtSegments("aaa.bbb"); err == nil {
+		t.Fatal("expected an error for a token without 3 segments")
+	}
+}
+
+func TestSignJWTAndVerifyJWTRoundTrip(t *testing.T) {
+	token, err := signJWT([]byte(`{"iss":"issuer1"}`), "HS256", "shared-secret", "")
+	if err != nil {
+		t.Fatalf("signJWT() error = %v", err)
+	}
+	if strings.Count(token, ".") != 2 {
+		t.Fatalf("expected a token with 3 segments, got %q", token)
+	}
+
+	if err := verifyJWT(token, "shared-secret", ""); err != nil {
+		t.Fatalf("verifyJWT() error = %v", err)
+	}
+
+	if err := verifyJWT(token, "wrong-secret", ""); err == nil {
+		t.Fatal("expected verifyJWT() to fail with the wrong secret")
+	}
+}
+
+func TestCheckClaimsIssMismatch(t *testing.T) {
+	err := checkClaims([]byte(`{"iss":"issuer1"}`), "issuer2", "", false)
+	if err == nil {
+		t.Fatal("expected an error for a mismatched iss claim")
+	}
+}
+
+func TestCheckClaimsAudAcceptsStringOrArray(t *testing.T) {
+	if err := checkClaims([]byte(`{"aud":"svcA"}`), "", "svcA", false); err != nil {
+		t.Fatalf("checkClaims() with string aud error = %v", err)
+	}
+
+	if err := checkClaims([]byte(`{"aud":["svcA","svcB"]}`), "", "svcB", false); err != nil {
+		t.Fatalf("checkClaims() with array aud error = %v", err)
+	}
+
+	if err := checkClaims([]byte(`{"aud":["svcA","svcB"]}`), "", "svcC", false); err == nil {
+		t.Fatal("expected an error when the wanted aud is not present")
+	}
+}
+
+func TestCheckClaimsIgnoresUncheckedAud(t *testing.T) {
+	if err := checkClaims([]byte(`{"iss":"issuer1","aud":["svcA","svcB"]}`), "issuer1", "", false); err != nil {
+		t.Fatalf("checkClaims() error = %v", err)
+	}
+}
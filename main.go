@@ -3,166 +3,1074 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
 	"flag"
 	"fmt"
+	"hash"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
 	"io"
+	"io/fs"
+	"math/big"
 	"os"
+	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
+	"github.com/pechorka/dev-tools/internal/cli"
 	"github.com/pechorka/gostdlib/pkg/clipboard"
 	"github.com/pechorka/gostdlib/pkg/errs"
 	"github.com/pechorka/gostdlib/pkg/uuid"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/term"
 )
 
 func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Kill, os.Interrupt)
 	defer cancel()
 
-	cmds := []Command{
-		newB64Command(),
-		newUuidCommand(),
-	}
-	err := run(ctx, cmds)
-	if err != nil {
-		usage(err, cmds)
+	root := newRootCommand()
+	if err := root.Execute(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(2)
 	}
 }
 
-func run(ctx context.Context, cmds []Command) error {
-	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
-		return errs.Wrap(err, "failed to pass cmdArgs")
+func newRootCommand() *cli.Command {
+	root := cli.New("dev-tools", "a grab-bag of small dev utilities")
+
+	root.AddSubcommand(newB64Command())
+	root.AddSubcommand(newUuidCommand())
+	root.AddSubcommand(newHashCommand())
+	root.AddSubcommand(newJwtCommand())
+	root.AddSubcommand(newWatchCommand())
+	root.AddSubcommand(newEncryptCommand())
+	root.AddSubcommand(newDecryptCommand())
+	root.AddSubcommand(newQrCommand())
+	root.AddSubcommand(newCompletionCommand(root))
+
+	return root
+}
+
+func newCompletionCommand(root *cli.Command) *cli.Command {
+	cmd := cli.New("completion", "generate a shell completion script")
+	cmd.Examples = []string{
+		"dev-tools completion bash > /etc/bash_completion.d/dev-tools",
+		`dev-tools completion zsh > "${fpath[1]}/_dev-tools"`,
 	}
 
-	rest := flag.Args()
-	if len(rest) == 0 {
-		return errs.New("could't figure out command")
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if len(args) != 1 {
+			return errs.New("completion requires exactly one shell argument: bash, zsh, or fish")
+		}
+
+		script, err := cli.Completion(root, args[0])
+		if err != nil {
+			return err
+		}
+
+		return writeOutput("", []byte(script))
 	}
 
-	cmdName, cmdArgs := rest[0], rest[1:]
+	return cmd
+}
+
+func newB64Command() *cli.Command {
+	cmd := cli.New("base64", "encode or decode base64 input")
+	cmd.Aliases = []string{"b64"}
+	fs := cmd.FS
 
-	for _, c := range cmds {
-		if c.Name == cmdName || c.Short == cmdName {
-			c.FS.Parse(cmdArgs)
-			return c.Run(ctx)
+	encode := boolAlias(fs, "e", "encode", true, "encode input")
+	decode := boolAlias(fs, "d", "decode", false, "decode input")
+	inputFile := stringAlias(fs, "in", "input", "", "input file")
+	inputText := stringAlias(fs, "t", "text", "", "input text")
+	outputPath := stringAlias(fs, "o", "output", "", "output file path. If empty, output will be printed to stdout")
+
+	cmd.MutuallyExclusive("e", "encode", "d", "decode")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		input, err := readInput(*inputFile, *inputText)
+		if err != nil {
+			return err
 		}
+
+		var output []byte
+		if *decode {
+			output, err = base64.RawStdEncoding.AppendDecode(nil, input)
+			if err != nil {
+				return errs.Wrap(err, "failed to encode content")
+			}
+		} else if *encode {
+			output = base64.RawStdEncoding.AppendEncode(nil, input)
+		}
+
+		return writeOutput(*outputPath, output)
 	}
 
-	return errs.Errorf("%s is unknown command", cmdName)
+	return cmd
 }
 
-func usage(err error, cmds []Command) {
-	fmt.Fprintf(os.Stderr, "%s\nUsage:\n", err.Error())
-	fmt.Fprintf(os.Stderr, "%s [global flags] <command> [flags]\n\n", os.Args[0])
-	fmt.Fprintln(os.Stderr, "Global flags:")
-	flag.PrintDefaults()
+func newUuidCommand() *cli.Command {
+	cmd := cli.New("uuid", "generate a random UUID")
+	cmd.Aliases = []string{"u"}
+	fs := cmd.FS
 
-	fmt.Fprintln(os.Stderr, "\nCommands:")
-	for _, c := range cmds {
-		fmt.Fprintf(os.Stderr, "  -%s (or -%s)\n", c.Name, c.Short)
+	v4 := fs.Bool("v4", true, "generate v4 uuid")
+	v7 := fs.Bool("v7", false, "generate v7 uuid")
+	crypto := boolAlias(fs, "crypto", "c", false, "use cryptographic random generator. Slower and may fail")
+
+	cmd.MutuallyExclusive("v4", "v7")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		var output string
+		var err error
+		switch {
+		case *v7 && *crypto:
+			output, err = uuid.NewV7CryptoString()
+		case *v7:
+			output = uuid.MustV7PseudoString()
+		case *v4 && *crypto:
+			output, err = uuid.NewV4CryptoString()
+		default:
+			output = uuid.MustV4PseudoString()
+		}
+		if err != nil {
+			return err
+		}
+
+		return writeOutput("", []byte(output))
 	}
-	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for details.", os.Args[0])
+
+	return cmd
 }
 
-type Command struct {
-	Name, Short string
-	FS          *flag.FlagSet
-	Run         func(ctx context.Context) error
+// InputSource resolves one of the input flags that commands like base64 and
+// hash accept (flag text, a file, stdin, or the clipboard) into a stream, so
+// callers can choose to buffer it or read it incrementally.
+type InputSource struct {
+	filePath, text string
 }
 
-func newB64Command() Command {
-	const name = "base64"
-	fs := newFlagSet(name)
+func newInputSource(filePath, text string) InputSource {
+	return InputSource{filePath: filePath, text: text}
+}
 
-	encode := boolAlias(fs, "e", "encode", true, "encode input")
-	decode := boolAlias(fs, "d", "decode", false, "decode input")
+// Open resolves the source and returns an io.ReadCloser. The caller is
+// responsible for closing it.
+func (is InputSource) Open() (io.ReadCloser, error) {
+	if is.text != "" {
+		// TODO:implement custom flag that will allow to provide byte input
+		return io.NopCloser(strings.NewReader(is.text)), nil
+	}
+
+	if is.filePath != "" {
+		f, err := os.Open(is.filePath)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to open file %s", is.filePath)
+		}
+		return f, nil
+	}
+
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to stat stdin")
+	}
+	if fi.Mode()&os.ModeCharDevice == 0 {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	clipboardContent, err := clipboard.Read()
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to read clipboard content")
+	}
+	if len(clipboardContent) == 0 {
+		return nil, errs.New("no input provided")
+	}
+
+	return io.NopCloser(bytes.NewReader(clipboardContent)), nil
+}
+
+func newHashCommand() *cli.Command {
+	cmd := cli.New("hash", "compute streaming digests over the input")
+	cmd.Aliases = []string{"h"}
+	fs := cmd.FS
+
+	algos := stringAlias(fs, "a", "algo", "sha256", "comma-separated list of algorithms to digest: md5, sha1, sha256, sha512, blake2b, blake2b-256")
+	format := stringAlias(fs, "f", "format", "hex", "output format: hex, base64, raw")
 	inputFile := stringAlias(fs, "in", "input", "", "input file")
 	inputText := stringAlias(fs, "t", "text", "", "input text")
 	outputPath := stringAlias(fs, "o", "output", "", "output file path. If empty, output will be printed to stdout")
+	verify := fs.String("verify", "", "expected digest to compare against the computed one (requires a single algorithm)")
 
-	return Command{
-		Name:  name,
-		Short: "b64",
-		FS:    fs,
-		Run: func(ctx context.Context) error {
-			input, err := readInput(*inputFile, *inputText)
+	cmd.Run = func(ctx context.Context, args []string) error {
+		names := strings.Split(*algos, ",")
+		for i := range names {
+			names[i] = strings.TrimSpace(strings.ToLower(names[i]))
+		}
+
+		if *verify != "" && len(names) != 1 {
+			return errs.New("-verify requires exactly one algorithm")
+		}
+
+		hashers := make([]hash.Hash, len(names))
+		writers := make([]io.Writer, len(names))
+		for i, n := range names {
+			h, err := newHasher(n)
 			if err != nil {
 				return err
 			}
+			hashers[i] = h
+			writers[i] = h
+		}
 
-			var output []byte
-			if *decode {
-				output, err = base64.RawStdEncoding.AppendDecode(nil, input)
-				if err != nil {
-					return errs.Wrap(err, "failed to encode content")
-				}
-			} else if *encode {
-				output = base64.RawStdEncoding.AppendEncode(nil, input)
-			}
+		rc, err := newInputSource(*inputFile, *inputText).Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		if _, err := io.Copy(io.MultiWriter(writers...), rc); err != nil {
+			return errs.Wrap(err, "failed to digest input")
+		}
 
-			err = writeOutput(*outputPath, output)
+		var sb strings.Builder
+		for i, n := range names {
+			digest, err := encodeDigest(hashers[i].Sum(nil), *format)
 			if err != nil {
 				return err
 			}
+			if len(names) > 1 {
+				fmt.Fprintf(&sb, "%s  %s\n", n, digest)
+			} else {
+				fmt.Fprintf(&sb, "%s\n", digest)
+			}
+		}
 
-			return nil
-		},
+		if *verify != "" {
+			got := strings.TrimSpace(sb.String())
+			if !digestMatches(*format, got, strings.TrimSpace(*verify)) {
+				return errs.Errorf("digest mismatch: got %s, want %s", got, *verify)
+			}
+		}
+
+		return writeOutput(*outputPath, []byte(sb.String()))
 	}
+
+	return cmd
 }
 
-func newUuidCommand() Command {
-	const name = "uuid"
-	fs := newFlagSet(name)
+func newHasher(algo string) (hash.Hash, error) {
+	switch algo {
+	case "md5":
+		return md5.New(), nil
+	case "sha1":
+		return sha1.New(), nil
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	case "blake2b":
+		h, err := blake2b.New512(nil)
+		if err != nil {
+			return nil, errs.Wrap(err, "failed to create blake2b hasher")
+		}
+		return h, nil
+	case "blake2b-256":
+		h, err := blake2b.New256(nil)
+		if err != nil {
+			return nil, errs.Wrap(err, "failed to create blake2b-256 hasher")
+		}
+		return h, nil
+	default:
+		return nil, errs.Errorf("%s is unknown algorithm", algo)
+	}
+}
 
-	v4 := fs.Bool("v4", true, "generate v4 uuid")
-	v7 := fs.Bool("v7", true, "generate v7 uuid")
-	crypto := boolAlias(fs, "crypto", "c", false, "use cryptographic random generator. Slower and may fail")
+// digestMatches reports whether got and want are the same digest in the
+// given format. hex digests are conventionally compared case-insensitively;
+// base64/raw are case-sensitive, so only hex folds case.
+func digestMatches(format, got, want string) bool {
+	if format == "hex" {
+		return strings.EqualFold(got, want)
+	}
+	return got == want
+}
+
+func encodeDigest(sum []byte, format string) (string, error) {
+	switch format {
+	case "hex":
+		return hex.EncodeToString(sum), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(sum), nil
+	case "raw":
+		return string(sum), nil
+	default:
+		return "", errs.Errorf("%s is unknown format", format)
+	}
+}
 
-	return Command{
-		Name:  name,
-		Short: "b64",
-		FS:    fs,
-		Run: func(ctx context.Context) error {
-			var output string
-			var err error
-			switch {
-			case *v4 && !*crypto:
-				output = uuid.MustV4PseudoString()
-			case *v4 && *crypto:
-				output, err = uuid.NewV4CryptoString()
-			case *v7 && !*crypto:
-				output = uuid.MustV7PseudoString()
-			case *v7 && *crypto:
-				output, err = uuid.NewV4CryptoString()
+func newJwtCommand() *cli.Command {
+	cmd := cli.New("jwt", "decode, verify, or sign a JSON Web Token")
+	cmd.Aliases = []string{"j"}
+	fs := cmd.FS
+
+	inputFile := stringAlias(fs, "in", "input", "", "input file containing the token")
+	inputText := stringAlias(fs, "t", "text", "", "input text (the token)")
+	outputPath := stringAlias(fs, "o", "output", "", "output file path. If empty, output will be printed to stdout")
+
+	verify := fs.Bool("verify", false, "verify the token signature instead of only decoding it")
+	secret := fs.String("secret", "", "HMAC secret used to verify/sign (HS256/HS384/HS512)")
+	keyPath := fs.String("key", "", "path to a PEM key used to verify/sign (RS256 public/private key, ES256 public/private key)")
+	claims := fs.Bool("claims", false, "also validate the exp/nbf registered claims")
+	iss := fs.String("iss", "", "expected issuer, checked against the iss claim when set")
+	aud := fs.String("aud", "", "expected audience, checked against the aud claim when set")
+
+	sign := fs.String("sign", "", "path to a JSON payload file; when set, a new token is signed instead of decoding -in/-text")
+	alg := fs.String("alg", "HS256", "signing algorithm used with -sign: HS256, HS384, HS512, RS256, ES256")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		if *sign != "" {
+			payload, err := os.ReadFile(*sign)
+			if err != nil {
+				return errs.Wrapf(err, "failed to read payload file %s", *sign)
 			}
+
+			token, err := signJWT(payload, *alg, *secret, *keyPath)
 			if err != nil {
 				return err
 			}
 
-			err = writeOutput("", []byte(output))
+			return writeOutput(*outputPath, []byte(token))
+		}
+
+		input, err := readInput(*inputFile, *inputText)
+		if err != nil {
+			return err
+		}
+		token := strings.TrimSpace(string(input))
+
+		header, payload, err := decodeJWT(token)
+		if err != nil {
+			return err
+		}
+
+		if *verify {
+			if err := verifyJWT(token, *secret, *keyPath); err != nil {
+				return err
+			}
+		}
+
+		if *claims || *iss != "" || *aud != "" {
+			if err := checkClaims(payload, *iss, *aud, *claims); err != nil {
+				return err
+			}
+		}
+
+		var out bytes.Buffer
+		fmt.Fprintln(&out, "header:")
+		out.Write(header)
+		fmt.Fprintln(&out)
+		fmt.Fprintln(&out, "payload:")
+		out.Write(payload)
+		fmt.Fprintln(&out)
+
+		return writeOutput(*outputPath, out.Bytes())
+	}
+
+	return cmd
+}
+
+// jwtSegments splits a JWT into its base64url-encoded header, payload and
+// signature segments without decoding them.
+func jwtSegments(token string) (header, payload, signature string, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", "", "", errs.Errorf("token must have 3 segments, got %d", len(parts))
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+func decodeJWT(token string) (header, payload []byte, err error) {
+	headerSeg, payloadSeg, _, err := jwtSegments(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(headerSeg)
+	if err != nil {
+		return nil, nil, errs.Wrap(err, "failed to decode header")
+	}
+	rawPayload, err := base64.RawURLEncoding.DecodeString(payloadSeg)
+	if err != nil {
+		return nil, nil, errs.Wrap(err, "failed to decode payload")
+	}
+
+	header, err = prettyJSON(rawHeader)
+	if err != nil {
+		return nil, nil, errs.Wrap(err, "failed to format header")
+	}
+	payload, err = prettyJSON(rawPayload)
+	if err != nil {
+		return nil, nil, errs.Wrap(err, "failed to format payload")
+	}
+
+	return header, payload, nil
+}
+
+func prettyJSON(raw []byte) ([]byte, error) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, raw, "", "  "); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// stringOrSlice decodes a JSON claim that RFC 7519 allows to be encoded as
+// either a single string or an array of strings, such as the "aud" claim.
+type stringOrSlice []string
+
+func (s *stringOrSlice) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = stringOrSlice{single}
+		return nil
+	}
+
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*s = stringOrSlice(multi)
+	return nil
+}
+
+func (s stringOrSlice) contains(v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func checkClaims(payload []byte, wantIss, wantAud string, checkTimes bool) error {
+	var claims struct {
+		Exp json.Number   `json:"exp"`
+		Nbf json.Number   `json:"nbf"`
+		Iss string        `json:"iss"`
+		Aud stringOrSlice `json:"aud"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return errs.Wrap(err, "failed to unmarshal claims")
+	}
+
+	now := time.Now().Unix()
+
+	if checkTimes && claims.Exp != "" {
+		exp, err := claims.Exp.Int64()
+		if err != nil {
+			return errs.Wrap(err, "failed to parse exp claim")
+		}
+		if now >= exp {
+			return errs.Errorf("token expired at %s", time.Unix(exp, 0))
+		}
+	}
+
+	if checkTimes && claims.Nbf != "" {
+		nbf, err := claims.Nbf.Int64()
+		if err != nil {
+			return errs.Wrap(err, "failed to parse nbf claim")
+		}
+		if now < nbf {
+			return errs.Errorf("token not valid before %s", time.Unix(nbf, 0))
+		}
+	}
+
+	if wantIss != "" && claims.Iss != wantIss {
+		return errs.Errorf("iss claim %q does not match expected %q", claims.Iss, wantIss)
+	}
+
+	if wantAud != "" && !claims.Aud.contains(wantAud) {
+		return errs.Errorf("aud claim %q does not match expected %q", []string(claims.Aud), wantAud)
+	}
+
+	return nil
+}
+
+func verifyJWT(token, secret, keyPath string) error {
+	headerSeg, payloadSeg, sigSeg, err := jwtSegments(token)
+	if err != nil {
+		return err
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(headerSeg)
+	if err != nil {
+		return errs.Wrap(err, "failed to decode header")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(rawHeader, &header); err != nil {
+		return errs.Wrap(err, "failed to unmarshal header")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(sigSeg)
+	if err != nil {
+		return errs.Wrap(err, "failed to decode signature")
+	}
+
+	signingInput := headerSeg + "." + payloadSeg
+
+	switch header.Alg {
+	case "HS256", "HS384", "HS512":
+		if secret == "" {
+			return errs.New("-secret is required to verify an HMAC token")
+		}
+		expected, err := hmacSign([]byte(signingInput), []byte(secret), header.Alg)
+		if err != nil {
+			return err
+		}
+		if !hmac.Equal(sig, expected) {
+			return errs.New("signature verification failed")
+		}
+		return nil
+	case "RS256":
+		key, err := loadRSAPublicKey(keyPath)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], sig); err != nil {
+			return errs.Wrap(err, "signature verification failed")
+		}
+		return nil
+	case "ES256":
+		key, err := loadECPublicKey(keyPath)
+		if err != nil {
+			return err
+		}
+		if len(sig) != 64 {
+			return errs.Errorf("unexpected ES256 signature length %d", len(sig))
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		sum := sha256.Sum256([]byte(signingInput))
+		if !ecdsa.Verify(key, sum[:], r, s) {
+			return errs.New("signature verification failed")
+		}
+		return nil
+	default:
+		return errs.Errorf("%s is unsupported alg", header.Alg)
+	}
+}
+
+func signJWT(payload []byte, alg, secret, keyPath string) (string, error) {
+	headerJSON, err := json.Marshal(struct {
+		Alg string `json:"alg"`
+		Typ string `json:"typ"`
+	}{Alg: alg, Typ: "JWT"})
+	if err != nil {
+		return "", errs.Wrap(err, "failed to marshal header")
+	}
+
+	var compactPayload bytes.Buffer
+	if err := json.Compact(&compactPayload, payload); err != nil {
+		return "", errs.Wrap(err, "failed to compact payload")
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(compactPayload.Bytes())
+
+	var sig []byte
+	switch alg {
+	case "HS256", "HS384", "HS512":
+		if secret == "" {
+			return "", errs.New("-secret is required to sign an HMAC token")
+		}
+		sig, err = hmacSign([]byte(signingInput), []byte(secret), alg)
+		if err != nil {
+			return "", err
+		}
+	case "RS256":
+		key, err := loadRSAPrivateKey(keyPath)
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256([]byte(signingInput))
+		sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+		if err != nil {
+			return "", errs.Wrap(err, "failed to sign token")
+		}
+	case "ES256":
+		key, err := loadECPrivateKey(keyPath)
+		if err != nil {
+			return "", err
+		}
+		sum := sha256.Sum256([]byte(signingInput))
+		r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+		if err != nil {
+			return "", errs.Wrap(err, "failed to sign token")
+		}
+		sig = append(fixedBytes(r, 32), fixedBytes(s, 32)...)
+	default:
+		return "", errs.Errorf("%s is unsupported alg", alg)
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func hmacSign(data, secret []byte, alg string) ([]byte, error) {
+	var h func() hash.Hash
+	switch alg {
+	case "HS256":
+		h = sha256.New
+	case "HS384":
+		h = sha512.New384
+	case "HS512":
+		h = sha512.New
+	default:
+		return nil, errs.Errorf("%s is unsupported HMAC alg", alg)
+	}
+
+	mac := hmac.New(h, secret)
+	mac.Write(data)
+	return mac.Sum(nil), nil
+}
+
+func fixedBytes(n *big.Int, size int) []byte {
+	b := n.Bytes()
+	if len(b) >= size {
+		return b
+	}
+	out := make([]byte, size)
+	copy(out[size-len(b):], b)
+	return out
+}
+
+func loadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if pub, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		if key, ok := pub.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+	}
+	if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+	}
+	return nil, errs.Errorf("%s does not contain an RSA public key", path)
+}
+
+func loadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+	}
+	return nil, errs.Errorf("%s does not contain an RSA private key", path)
+}
+
+func loadECPublicKey(path string) (*ecdsa.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if pub, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		if key, ok := pub.(*ecdsa.PublicKey); ok {
+			return key, nil
+		}
+	}
+	return nil, errs.Errorf("%s does not contain an EC public key", path)
+}
+
+func loadECPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if ecKey, ok := key.(*ecdsa.PrivateKey); ok {
+			return ecKey, nil
+		}
+	}
+	return nil, errs.Errorf("%s does not contain an EC private key", path)
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	if path == "" {
+		return nil, errs.New("-key is required for this algorithm")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to read key file %s", path)
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errs.Errorf("%s does not contain a PEM block", path)
+	}
+	return block, nil
+}
+
+func newWatchCommand() *cli.Command {
+	cmd := cli.New("watch", "re-run a command whenever watched files change")
+	cmd.Aliases = []string{"w"}
+	cmd.Examples = []string{
+		"dev-tools watch -paths ./src -patterns **/*.go -- go run .",
+	}
+	fs := cmd.FS
+
+	paths := stringAlias(fs, "p", "paths", ".", "comma-separated files or directories to watch")
+	patterns := stringAlias(fs, "pat", "patterns", "**/*", "comma-separated glob patterns (matched against the file name, e.g. **/*.go)")
+	exclude := stringAlias(fs, "e", "exclude", `\.git`, "comma-separated regexes; matching paths are ignored")
+	depth := fs.Int("depth", -1, "how many directory levels to recurse into; -1 means unlimited")
+	delay := fs.Duration("delay", 100*time.Millisecond, "debounce window: events within this window are coalesced into a single restart")
+	sig := stringAlias(fs, "s", "signal", "TERM", "signal sent to the previous process group before restarting: TERM or KILL")
+
+	cmd.Run = func(ctx context.Context, cmdLine []string) error {
+		if len(cmdLine) == 0 {
+			return errs.New("missing command to run after --")
+		}
+
+		signum, err := parseSignal(*sig)
+		if err != nil {
+			return err
+		}
+
+		w, err := newWatcher(strings.Split(*paths, ","), *depth, strings.Split(*patterns, ","), strings.Split(*exclude, ","))
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+
+		return runWatch(ctx, w, *delay, signum, cmdLine)
+	}
+
+	return cmd
+}
+
+// watcher wraps fsnotify.Watcher with the pattern/exclude matching watch
+// needs to decide whether a given filesystem event should trigger a restart.
+type watcher struct {
+	fsw      *fsnotify.Watcher
+	patterns []string
+	exclude  []*regexp.Regexp
+}
+
+func newWatcher(paths []string, depth int, patterns, excludes []string) (*watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to create fsnotify watcher")
+	}
+
+	var excludeRe []*regexp.Regexp
+	for _, e := range excludes {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		re, err := regexp.Compile(e)
+		if err != nil {
+			fsw.Close()
+			return nil, errs.Wrapf(err, "invalid -exclude pattern %q", e)
+		}
+		excludeRe = append(excludeRe, re)
+	}
+
+	w := &watcher{fsw: fsw, patterns: patterns, exclude: excludeRe}
+
+	for _, p := range paths {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if err := w.add(p, depth); err != nil {
+			fsw.Close()
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+func (w *watcher) add(root string, depth int) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if w.isExcluded(path) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			// root itself may name a file rather than a directory; fsnotify
+			// can watch files directly, so register it instead of skipping
+			// it. Files discovered while walking a directory are covered by
+			// the watch on their parent directory and don't need this.
+			if path == root {
+				if err := w.fsw.Add(path); err != nil {
+					return errs.Wrapf(err, "failed to watch %s", path)
+				}
+			}
+			return nil
+		}
+		if depth >= 0 {
+			rel, err := filepath.Rel(root, path)
 			if err != nil {
 				return err
 			}
+			if rel != "." && strings.Count(rel, string(filepath.Separator))+1 > depth {
+				return filepath.SkipDir
+			}
+		}
+		if err := w.fsw.Add(path); err != nil {
+			return errs.Wrapf(err, "failed to watch %s", path)
+		}
+		return nil
+	})
+}
+
+func (w *watcher) isExcluded(path string) bool {
+	for _, re := range w.exclude {
+		if re.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *watcher) matches(path string) bool {
+	if w.isExcluded(path) {
+		return false
+	}
+	base := filepath.Base(path)
+	for _, p := range w.patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if idx := strings.LastIndex(p, "/"); idx >= 0 {
+			p = p[idx+1:]
+		}
+		if ok, _ := filepath.Match(p, base); ok {
+			return true
+		}
+	}
+	return false
+}
 
+func (w *watcher) Close() error {
+	return w.fsw.Close()
+}
+
+func parseSignal(name string) (syscall.Signal, error) {
+	switch strings.ToUpper(name) {
+	case "TERM":
+		return syscall.SIGTERM, nil
+	case "KILL":
+		return syscall.SIGKILL, nil
+	default:
+		return 0, errs.Errorf("%s is unsupported signal", name)
+	}
+}
+
+// runWatch spawns cmdLine in its own process group, restarting it whenever a
+// watched event survives the debounce window, until ctx is done.
+func runWatch(ctx context.Context, w *watcher, delay time.Duration, sig syscall.Signal, cmdLine []string) error {
+	var timer *time.Timer
+	restart := make(chan struct{}, 1)
+	debounce := func() {
+		if timer == nil {
+			timer = time.AfterFunc(delay, func() {
+				select {
+				case restart <- struct{}{}:
+				default:
+				}
+			})
+			return
+		}
+		timer.Reset(delay)
+	}
+
+	var proc *os.Process
+	start := func() error {
+		cmd := exec.Command(cmdLine[0], cmdLine[1:]...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Stdin = os.Stdin
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		if err := cmd.Start(); err != nil {
+			return errs.Wrapf(err, "failed to start %s", cmdLine[0])
+		}
+		proc = cmd.Process
+		go cmd.Wait()
+		return nil
+	}
+	stop := func() {
+		if proc == nil {
+			return
+		}
+		syscall.Kill(-proc.Pid, sig)
+		proc = nil
+	}
+
+	if err := start(); err != nil {
+		return err
+	}
+	defer stop()
+
+	for {
+		select {
+		case <-ctx.Done():
 			return nil
-		},
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return nil
+			}
+			if w.matches(event.Name) {
+				debounce()
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(os.Stderr, "watch error: %v\n", err)
+		case <-restart:
+			stop()
+			if err := start(); err != nil {
+				return err
+			}
+		}
 	}
 }
 
-func readInput(filePath, text string) ([]byte, error) {
-	if text != "" {
-		// TODO:implement custom flag that will allow to provide byte input
-		return []byte(text), nil
+const (
+	vaultHeader    = "$DEVTOOLS_VAULT;AEAD=xchacha20poly1305;KDF=argon2id;t=3;m=65536;p=4$"
+	vaultSaltSize  = 16
+	vaultNonceSize = chacha20poly1305.NonceSizeX
+	argonTime      = 3
+	argonMemoryKiB = 64 * 1024
+	argonThreads   = 4
+	argonKeySize   = 32
+)
+
+func newEncryptCommand() *cli.Command {
+	cmd := cli.New("encrypt", "encrypt input with a passphrase-derived key")
+	cmd.Aliases = []string{"enc"}
+	fs := cmd.FS
+
+	inputFile := stringAlias(fs, "in", "input", "", "input file")
+	inputText := stringAlias(fs, "t", "text", "", "input text")
+	outputPath := stringAlias(fs, "o", "output", "", "output file path. If empty, output will be printed to stdout")
+	passFile := fs.String("pass-file", "", "file containing the passphrase on its first line")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		input, err := readInput(*inputFile, *inputText)
+		if err != nil {
+			return err
+		}
+
+		pass, err := readPassphrase(*passFile)
+		if err != nil {
+			return err
+		}
+
+		armored, err := vaultEncrypt(input, pass)
+		if err != nil {
+			return err
+		}
+
+		return writeOutput(*outputPath, armored)
 	}
 
-	if filePath != "" {
-		fileContent, err := os.ReadFile(filePath)
+	return cmd
+}
+
+func newDecryptCommand() *cli.Command {
+	cmd := cli.New("decrypt", "decrypt a payload produced by encrypt")
+	cmd.Aliases = []string{"dec"}
+	fs := cmd.FS
+
+	inputFile := stringAlias(fs, "in", "input", "", "input file")
+	inputText := stringAlias(fs, "t", "text", "", "input text")
+	outputPath := stringAlias(fs, "o", "output", "", "output file path. If empty, output will be printed to stdout")
+	passFile := fs.String("pass-file", "", "file containing the passphrase on its first line")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		input, err := readInput(*inputFile, *inputText)
 		if err != nil {
-			return nil, errs.Wrapf(err, "failed to read file %s", filePath)
+			return err
 		}
 
-		return fileContent, nil
+		pass, err := readPassphrase(*passFile)
+		if err != nil {
+			return err
+		}
+
+		plain, err := vaultDecrypt(input, pass)
+		if err != nil {
+			return err
+		}
+
+		return writeOutput(*outputPath, plain)
+	}
+
+	return cmd
+}
+
+// readPassphrase resolves the vault passphrase from, in order, -pass-file,
+// $DEVTOOLS_PASS, or an interactive prompt when stdin is a TTY.
+func readPassphrase(passFile string) ([]byte, error) {
+	if passFile != "" {
+		raw, err := os.ReadFile(passFile)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to read pass file %s", passFile)
+		}
+		line, _, _ := bytes.Cut(raw, []byte("\n"))
+		return bytes.TrimRight(line, "\r"), nil
+	}
+
+	if pass := os.Getenv("DEVTOOLS_PASS"); pass != "" {
+		return []byte(pass), nil
 	}
 
 	fi, err := os.Stdin.Stat()
@@ -170,22 +1078,272 @@ func readInput(filePath, text string) ([]byte, error) {
 		return nil, errs.Wrap(err, "failed to stat stdin")
 	}
 	if fi.Mode()&os.ModeCharDevice == 0 {
-		stdinContent, err := io.ReadAll(os.Stdin)
+		return nil, errs.New("no passphrase provided: use -pass-file or $DEVTOOLS_PASS when stdin is piped")
+	}
+
+	fmt.Fprint(os.Stderr, "passphrase: ")
+	pass, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to read passphrase")
+	}
+
+	return pass, nil
+}
+
+func vaultEncrypt(plaintext, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, vaultSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errs.Wrap(err, "failed to generate salt")
+	}
+
+	aead, err := newVaultAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errs.Wrap(err, "failed to generate nonce")
+	}
+
+	sealed := aead.Seal(nil, nonce, plaintext, nil)
+
+	payload := make([]byte, 0, len(salt)+len(nonce)+len(sealed))
+	payload = append(payload, salt...)
+	payload = append(payload, nonce...)
+	payload = append(payload, sealed...)
+
+	out := append([]byte(vaultHeader), '\n')
+	out = base64.StdEncoding.AppendEncode(out, payload)
+
+	return out, nil
+}
+
+func vaultDecrypt(armored, passphrase []byte) ([]byte, error) {
+	idx := bytes.IndexByte(armored, '\n')
+	if idx < 0 {
+		return nil, errs.New("malformed vault payload: missing header")
+	}
+	if header := string(bytes.TrimSpace(armored[:idx])); header != vaultHeader {
+		return nil, errs.Errorf("unsupported vault header %q", header)
+	}
+
+	payload, err := base64.StdEncoding.AppendDecode(nil, bytes.TrimSpace(armored[idx+1:]))
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to decode vault payload")
+	}
+	if len(payload) < vaultSaltSize+vaultNonceSize {
+		return nil, errs.New("malformed vault payload: too short")
+	}
+
+	salt := payload[:vaultSaltSize]
+	nonce := payload[vaultSaltSize : vaultSaltSize+vaultNonceSize]
+	ciphertext := payload[vaultSaltSize+vaultNonceSize:]
+
+	aead, err := newVaultAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to decrypt: wrong passphrase or corrupted data")
+	}
+
+	return plaintext, nil
+}
+
+func newVaultAEAD(passphrase, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey(passphrase, salt, argonTime, argonMemoryKiB, argonThreads, argonKeySize)
+
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to create AEAD cipher")
+	}
+
+	return aead, nil
+}
+
+func newQrCommand() *cli.Command {
+	cmd := cli.New("qr", "render a QR code to the terminal, a PNG, or an SVG")
+	cmd.Aliases = []string{"q"}
+	fs := cmd.FS
+
+	inputFile := stringAlias(fs, "in", "input", "", "input file")
+	inputText := stringAlias(fs, "t", "text", "", "input text")
+	outputPath := stringAlias(fs, "o", "output", "", "output file path. If empty, output will be printed to stdout")
+	out := fs.String("out", "", "output format: ansi, png, svg. Defaults to ansi when -o is not set and stdout is a TTY, png otherwise")
+	ecc := fs.String("ecc", "M", "error correction level: L, M, Q, H")
+	size := fs.Int("size", 8, "module pixel size for png output")
+	quiet := fs.Int("quiet", 4, "quiet-zone width in modules")
+
+	cmd.Run = func(ctx context.Context, args []string) error {
+		input, err := readInput(*inputFile, *inputText)
 		if err != nil {
-			return nil, errs.Wrap(err, "failed to read text from stdin")
+			return err
 		}
-		return stdinContent, nil
+
+		level, err := qrECCLevel(*ecc)
+		if err != nil {
+			return err
+		}
+
+		format := *out
+		if format == "" {
+			if *outputPath == "" && term.IsTerminal(int(os.Stdout.Fd())) {
+				format = "ansi"
+			} else {
+				format = "png"
+			}
+		}
+
+		qr, err := qrcode.New(string(input), level)
+		if err != nil {
+			return errs.Wrap(err, "failed to build qr code")
+		}
+		qr.DisableBorder = true
+		bitmap := qr.Bitmap()
+
+		var output []byte
+		switch format {
+		case "ansi":
+			output = []byte(renderQRAnsi(bitmap, *quiet))
+		case "png":
+			output, err = renderQRPNG(bitmap, *quiet, *size)
+			if err != nil {
+				return err
+			}
+		case "svg":
+			output = []byte(renderQRSVG(bitmap, *quiet, *size))
+		default:
+			return errs.Errorf("%s is unknown output format", format)
+		}
+
+		return writeOutput(*outputPath, output)
 	}
 
-	clipboardContent, err := clipboard.Read()
+	return cmd
+}
+
+func qrECCLevel(level string) (qrcode.RecoveryLevel, error) {
+	switch strings.ToUpper(level) {
+	case "L":
+		return qrcode.Low, nil
+	case "M":
+		return qrcode.Medium, nil
+	case "Q":
+		return qrcode.High, nil
+	case "H":
+		return qrcode.Highest, nil
+	default:
+		return 0, errs.Errorf("%s is unknown ecc level", level)
+	}
+}
+
+// renderQRAnsi packs two bitmap rows into a single terminal row using the
+// upper-half-block character, so the printed code stays square in typical
+// monospace fonts.
+func renderQRAnsi(bitmap [][]bool, quiet int) string {
+	width := len(bitmap[0]) + 2*quiet
+	height := len(bitmap) + 2*quiet
+
+	dark := func(x, y int) bool {
+		x -= quiet
+		y -= quiet
+		if x < 0 || y < 0 || y >= len(bitmap) || x >= len(bitmap[0]) {
+			return false
+		}
+		return bitmap[y][x]
+	}
+
+	var sb strings.Builder
+	for y := 0; y < height; y += 2 {
+		for x := 0; x < width; x++ {
+			top := dark(x, y)
+			bottom := y+1 < height && dark(x, y+1)
+			sb.WriteString(ansiHalfBlock(top, bottom))
+		}
+		sb.WriteString("\x1b[0m\n")
+	}
+
+	return sb.String()
+}
+
+func ansiHalfBlock(top, bottom bool) string {
+	return fmt.Sprintf("\x1b[38;2;%s;48;2;%sm▀", moduleColor(top), moduleColor(bottom))
+}
+
+func moduleColor(dark bool) string {
+	if dark {
+		return "0;0;0"
+	}
+	return "255;255;255"
+}
+
+func renderQRPNG(bitmap [][]bool, quiet, moduleSize int) ([]byte, error) {
+	modules := len(bitmap) + 2*quiet
+	dim := modules * moduleSize
+
+	img := image.NewGray(image.Rect(0, 0, dim, dim))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	for y, row := range bitmap {
+		for x, isDark := range row {
+			if !isDark {
+				continue
+			}
+			px0, py0 := (x+quiet)*moduleSize, (y+quiet)*moduleSize
+			for dy := 0; dy < moduleSize; dy++ {
+				for dx := 0; dx < moduleSize; dx++ {
+					img.SetGray(px0+dx, py0+dy, color.Gray{})
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, errs.Wrap(err, "failed to encode png")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func renderQRSVG(bitmap [][]bool, quiet, moduleSize int) string {
+	modules := len(bitmap) + 2*quiet
+	dim := modules * moduleSize
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">`, dim, dim, dim, dim)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="#fff"/>`, dim, dim)
+	for y, row := range bitmap {
+		for x, isDark := range row {
+			if !isDark {
+				continue
+			}
+			fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" fill="#000"/>`,
+				(x+quiet)*moduleSize, (y+quiet)*moduleSize, moduleSize, moduleSize)
+		}
+	}
+	sb.WriteString(`</svg>`)
+
+	return sb.String()
+}
+
+func readInput(filePath, text string) ([]byte, error) {
+	rc, err := newInputSource(filePath, text).Open()
 	if err != nil {
-		return nil, errs.Wrap(err, "failed to read clipboard content")
+		return nil, err
 	}
-	if len(clipboardContent) > 0 {
-		return clipboardContent, nil
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to read input")
 	}
 
-	return nil, errs.New("no input provided")
+	return content, nil
 }
 
 func writeOutput(filePath string, data []byte) error {
@@ -204,16 +1362,6 @@ func writeOutput(filePath string, data []byte) error {
 	return nil
 }
 
-func newFlagSet(name string) *flag.FlagSet {
-	fs := flag.NewFlagSet(name, flag.ExitOnError)
-	fs.Usage = func() {
-		fmt.Fprintf(fs.Output(), "usage of %s: \n", name)
-		fs.PrintDefaults()
-	}
-
-	return fs
-}
-
 func stringAlias(fs *flag.FlagSet, short, long string, value string, usage string) *string {
 	var dst string
 	fs.StringVar(&dst, short, value, usage)
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+var testBitmap = [][]bool{
+	{true, false},
+	{false, true},
+}
+
+func TestRenderQRAnsiProducesOneLinePerTwoRows(t *testing.T) {
+	out := renderQRAnsi(testBitmap, 0)
+
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 line for a 2-row bitmap, got %d", len(lines))
+	}
+	if !strings.Contains(out, "\x1b[38;2;") {
+		t.Fatalf("expected ANSI truecolor escapes in output, got %q", out)
+	}
+}
+
+func TestRenderQRAnsiAppliesQuietZone(t *testing.T) {
+	without := renderQRAnsi(testBitmap, 0)
+	with := renderQRAnsi(testBitmap, 2)
+
+	if len(with) <= len(without) {
+		t.Fatalf("expected quiet zone to widen output: without=%d with=%d", len(without), len(with))
+	}
+}
+
+func TestRenderQRPNGProducesValidImage(t *testing.T) {
+	data, err := renderQRPNG(testBitmap, 1, 4)
+	if err != nil {
+		t.Fatalf("renderQRPNG() error = %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+
+	wantDim := (len(testBitmap) + 2*1) * 4
+	if img.Bounds().Dx() != wantDim || img.Bounds().Dy() != wantDim {
+		t.Fatalf("got image size %dx%d, want %dx%d", img.Bounds().Dx(), img.Bounds().Dy(), wantDim, wantDim)
+	}
+}
+
+func TestRenderQRSVGContainsExpectedElements(t *testing.T) {
+	svg := renderQRSVG(testBitmap, 1, 4)
+
+	if !strings.Contains(svg, "<svg") || !strings.Contains(svg, "</svg>") {
+		t.Fatalf("expected a well-formed svg document, got %q", svg)
+	}
+	if !strings.Contains(svg, `fill="#000"`) {
+		t.Fatal("expected at least one dark module rect")
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestVaultEncryptDecryptRoundTrip(t *testing.T) {
+	plaintext := []byte("the quick brown fox jumps over the lazy dog")
+	passphrase := []byte("correct horse battery staple")
+
+	armored, err := vaultEncrypt(plaintext, passphrase)
+	if err != nil {
+		t.Fatalf("vaultEncrypt() error = %v", err)
+	}
+
+	got, err := vaultDecrypt(armored, passphrase)
+	if err != nil {
+		t.Fatalf("vaultDecrypt() error = %v", err)
+	}
+	if string(got) != string(plaintext) {
+		t.Fatalf("vaultDecrypt() = %q, want %q", got, plaintext)
+	}
+}
+
+func TestVaultDecryptWrongPassphraseFails(t *testing.T) {
+	armored, err := vaultEncrypt([]byte("secret data"), []byte("right-passphrase"))
+	if err != nil {
+		t.Fatalf("vaultEncrypt() error = %v", err)
+	}
+
+	if _, err := vaultDecrypt(armored, []byte("wrong-passphrase")); err == nil {
+		t.Fatal("expected vaultDecrypt() to fail with the wrong passphrase")
+	}
+}
+
+func TestVaultDecryptRejectsMalformedPayload(t *testing.T) {
+	if _, err := vaultDecrypt([]byte("not a vault payload"), []byte("pass")); err == nil {
+		t.Fatal("expected an error for a payload without a header")
+	}
+	if _, err := vaultDecrypt([]byte(vaultHeader+"\nbm90IGVub3VnaA=="), []byte("pass")); err == nil {
+		t.Fatal("expected an error for a payload shorter than salt+nonce")
+	}
+}